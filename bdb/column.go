@@ -0,0 +1,30 @@
+package bdb
+
+// Column holds information about a database column.
+// Types are Go types, converted by TranslateColumnType.
+type Column struct {
+	Name    string
+	Type    string
+	DBType  string
+	UDTName string
+	Default string
+
+	Nullable  bool
+	Unique    bool
+	Validated bool
+
+	// FullText and FullTextIndexed describe a tsvector column: FullText
+	// is true for any tsvector column, FullTextIndexed additionally
+	// requires a GIN index on it (the only kind Search() can use).
+	FullText        bool
+	FullTextIndexed bool
+
+	// ArrType is true when DBType is "ARRAY" (UDTName, prefixed with an
+	// underscore, names the element type, e.g. "_int4").
+	ArrType bool
+
+	// IsEnum is true when UDTName names a Postgres enum type, in which
+	// case EnumValues holds its labels in declaration order.
+	IsEnum     bool
+	EnumValues []string
+}
@@ -0,0 +1,17 @@
+package drivers
+
+import (
+	"context"
+	"database/sql"
+)
+
+// DriverBackend abstracts the subset of *sql.DB that PostgresDriver
+// needs, so the connection it queries through can be backed by either
+// lib/pq (the default) or a pgx connection pool opened via
+// github.com/jackc/pgx/v4/stdlib. Both already satisfy this interface
+// since stdlib.OpenDB also returns a *sql.DB.
+type DriverBackend interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	Close() error
+}
@@ -1,12 +1,12 @@
 package drivers
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
 
-	// Side-effect import sql driver
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 	"github.com/vattle/sqlboiler/bdb"
 	"github.com/vattle/sqlboiler/strmangle"
@@ -15,8 +15,10 @@ import (
 // PostgresDriver holds the database connection string and a handle
 // to the database connection.
 type PostgresDriver struct {
-	connStr string
-	dbConn  *sql.DB
+	connStr          string
+	dbConn           DriverBackend
+	schemas          []string
+	packagePerSchema bool
 }
 
 // validatedTypes are types that cannot be zero values in the database.
@@ -25,15 +27,63 @@ var validatedTypes = []string{"uuid"}
 // NewPostgresDriver takes the database connection details as parameters and
 // returns a pointer to a PostgresDriver object. Note that it is required to
 // call PostgresDriver.Open() and PostgresDriver.Close() to open and close
-// the database connection once an object has been obtained.
+// the database connection once an object has been obtained. Schemas
+// defaults to []string{"public"}; use SetSchemas to introspect multiple
+// or non-default schemas. Open() connects via lib/pq; to introspect
+// through a pgx connection pool instead, use NewPostgresDriverWithBackend.
 func NewPostgresDriver(user, pass, dbname, host string, port int, sslmode string) *PostgresDriver {
 	driver := PostgresDriver{
 		connStr: BuildQueryString(user, pass, dbname, host, port, sslmode),
+		schemas: []string{"public"},
 	}
 
 	return &driver
 }
 
+// NewPostgresDriverWithBackend wraps an already-open DriverBackend, such
+// as a *sql.DB opened via github.com/jackc/pgx/v4/stdlib.OpenDB, so that
+// callers needing pgx's connection pooling and native Postgres type
+// support can bypass Open()/lib/pq entirely. Close() on the returned
+// driver closes backend.
+func NewPostgresDriverWithBackend(backend DriverBackend) *PostgresDriver {
+	return &PostgresDriver{
+		dbConn:  backend,
+		schemas: []string{"public"},
+	}
+}
+
+// SetSchemas overrides the schemas that will be introspected. TableNames
+// enumerates tables across all of them as (schema, name) pairs, and
+// every other introspection method takes the schema explicitly so a
+// table name that exists in more than one schema is never ambiguous.
+func (p *PostgresDriver) SetSchemas(schemas []string) {
+	p.schemas = schemas
+}
+
+// SetPackagePerSchema controls whether PackageNameFor groups generated
+// models by schema instead of putting every table in one package.
+// Defaults to false: all tables share a single package regardless of
+// how many schemas were passed to SetSchemas.
+func (p *PostgresDriver) SetPackagePerSchema(perSchema bool) {
+	p.packagePerSchema = perSchema
+}
+
+// PackageNameFor returns the Go package name table's generated model
+// belongs in. With SetPackagePerSchema(false) (the default) every table
+// shares defaultPkg; with it true, each schema gets its own package
+// named after the schema itself (e.g. "auth", "billing"), so two
+// schemas that both introspect a "users" table land in separate
+// packages instead of relying solely on the Schema-prefixed Go type
+// name (see tableNameGo's schema-collision handling) to tell them
+// apart.
+func (p *PostgresDriver) PackageNameFor(table bdb.Table, defaultPkg string) string {
+	if !p.packagePerSchema || table.Schema == "" {
+		return defaultPkg
+	}
+
+	return strmangle.CamelCase(table.Schema)
+}
+
 // BuildQueryString for Postgres
 func BuildQueryString(user, pass, dbname, host string, port int, sslmode string) string {
 	parts := []string{}
@@ -59,20 +109,22 @@ func BuildQueryString(user, pass, dbname, host string, port int, sslmode string)
 	return strings.Join(parts, " ")
 }
 
-// Open opens the database connection using the connection string
+// Open opens the database connection using the connection string, via
+// lib/pq. Use NewPostgresDriverWithBackend instead if you want to
+// connect through pgx.
 func (p *PostgresDriver) Open() error {
-	var err error
-	p.dbConn, err = sql.Open("postgres", p.connStr)
+	db, err := sql.Open("postgres", p.connStr)
 	if err != nil {
 		return err
 	}
+	p.dbConn = db
 
 	return nil
 }
 
 // Close closes the database connection
 func (p *PostgresDriver) Close() {
-	p.dbConn.Close()
+	_ = p.dbConn.Close()
 }
 
 // UseLastInsertID returns false for postgres
@@ -80,23 +132,44 @@ func (p *PostgresDriver) UseLastInsertID() bool {
 	return false
 }
 
-// TableNames connects to the postgres database and
-// retrieves all table names from the information_schema where the
-// table schema is public. It excludes common migration tool tables
-// such as gorp_migrations
-func (p *PostgresDriver) TableNames(exclude []string) ([]string, error) {
-	var names []string
+// relKinds are the pg_class.relkind values we introspect: ordinary
+// tables, views, materialized views, and foreign tables.
+const relKinds = `'r', 'v', 'm', 'f'`
+
+// TableIdentifier names a table together with the schema it lives in.
+// TableNames returns these, and every other introspection method takes
+// one apart as (schema, name), because the same table name can exist in
+// more than one of p.schemas and a bare name would be ambiguous.
+type TableIdentifier struct {
+	Schema string
+	Name   string
+}
 
-	query := `select table_name from information_schema.tables where table_schema = 'public'`
+// TableNames connects to the postgres database and retrieves the
+// (schema, name) of every table, view, materialized view, and foreign
+// table across p.schemas by querying pg_catalog.pg_class directly,
+// since information_schema.tables does not expose foreign tables. It
+// excludes common migration tool tables such as gorp_migrations.
+func (p *PostgresDriver) TableNames(ctx context.Context, exclude []string) ([]TableIdentifier, error) {
+	var idents []TableIdentifier
+
+	query := fmt.Sprintf(`
+	select n.nspname, c.relname
+	from pg_catalog.pg_class as c
+	inner join pg_catalog.pg_namespace as n on n.oid = c.relnamespace
+	where n.nspname = ANY($1) and c.relkind in (%s)`, relKinds)
+	args := []interface{}{pq.Array(p.schemas)}
 	if len(exclude) > 0 {
 		quoteStr := func(x string) string {
 			return `'` + x + `'`
 		}
 		exclude = strmangle.StringMap(quoteStr, exclude)
-		query = query + fmt.Sprintf("and table_name not in (%s);", strings.Join(exclude, ","))
+		query = query + fmt.Sprintf(" and c.relname not in (%s);", strings.Join(exclude, ","))
+	} else {
+		query = query + ";"
 	}
 
-	rows, err := p.dbConn.Query(query)
+	rows, err := p.dbConn.QueryContext(ctx, query, args...)
 
 	if err != nil {
 		return nil, err
@@ -104,25 +177,56 @@ func (p *PostgresDriver) TableNames(exclude []string) ([]string, error) {
 
 	defer rows.Close()
 	for rows.Next() {
-		var name string
-		if err := rows.Scan(&name); err != nil {
+		var ident TableIdentifier
+		if err := rows.Scan(&ident.Schema, &ident.Name); err != nil {
 			return nil, err
 		}
-		names = append(names, name)
+		idents = append(idents, ident)
+	}
+
+	return idents, nil
+}
+
+// TableKind looks up the pg_catalog relkind of schema.tableName so that
+// callers can tell base tables apart from views, materialized views,
+// and foreign tables. The returned value is stored on bdb.Table.Kind;
+// see TableInfo for what reads it.
+func (p *PostgresDriver) TableKind(ctx context.Context, schema, tableName string) (string, error) {
+	var relkind string
+
+	query := `
+	select c.relkind
+	from pg_catalog.pg_class as c
+	inner join pg_catalog.pg_namespace as n on n.oid = c.relnamespace
+	where n.nspname = $2 and c.relname = $1;`
+
+	row := p.dbConn.QueryRowContext(ctx, query, tableName, schema)
+	if err := row.Scan(&relkind); err != nil {
+		return "", err
 	}
 
-	return names, nil
+	switch relkind {
+	case "v":
+		return "view", nil
+	case "m":
+		return "materialized_view", nil
+	case "f":
+		return "foreign_table", nil
+	default:
+		return "table", nil
+	}
 }
 
-// Columns takes a table name and attempts to retrieve the table information
-// from the database information_schema.columns. It retrieves the column names
-// and column types and returns those as a []Column after TranslateColumnType()
-// converts the SQL types to Go types, for example: "varchar" to "string"
-func (p *PostgresDriver) Columns(tableName string) ([]bdb.Column, error) {
+// Columns takes a schema-qualified table and attempts to retrieve the
+// table information from the database information_schema.columns. It
+// retrieves the column names and column types and returns those as a
+// []Column after TranslateColumnType() converts the SQL types to Go
+// types, for example: "varchar" to "string"
+func (p *PostgresDriver) Columns(ctx context.Context, schema, tableName string) ([]bdb.Column, error) {
 	var columns []bdb.Column
 
-	rows, err := p.dbConn.Query(`
-		select column_name, data_type, column_default, is_nullable,
+	rows, err := p.dbConn.QueryContext(ctx, `
+		select column_name, data_type, udt_name, column_default, is_nullable,
 			(select exists(
 		    select 1
 				from information_schema.constraint_column_usage as ccu
@@ -136,11 +240,27 @@ func (p *PostgresDriver) Columns(tableName string) ([]bdb.Column, error) {
 		      inner join pg_index pgi on pgi.indexrelid = pgc.oid
 		      inner join pg_attribute pga on pga.attrelid = pgi.indrelid and pga.attnum = ANY(pgi.indkey)
 		    where
-		      pgix.schemaname = 'public' and pgix.tablename = c.table_name and pga.attname = c.column_name and pgi.indisunique = true
-		)) as is_unique
+		      pgix.schemaname = $2 and pgix.tablename = c.table_name and pga.attname = c.column_name and pgi.indisunique = true
+		)) as is_unique,
+			(select exists(
+		    select 1
+		    from
+		      pg_indexes pgix
+		      inner join pg_class pgc on pgix.indexname = pgc.relname and pgc.relkind = 'i'
+		      inner join pg_index pgi on pgi.indexrelid = pgc.oid
+		      inner join pg_attribute pga on pga.attrelid = pgi.indrelid and pga.attnum = ANY(pgi.indkey)
+		      inner join pg_am pgam on pgam.oid = pgc.relam
+		    where
+		      pgix.schemaname = $2 and pgix.tablename = c.table_name and pga.attname = c.column_name and pgam.amname = 'gin'
+		)) as is_gin_indexed,
+			(select array_agg(e.enumlabel order by e.enumsortorder)
+		    from pg_catalog.pg_type t
+		    inner join pg_catalog.pg_enum e on e.enumtypid = t.oid
+		    where t.typname = c.udt_name
+		) as enum_values
 		from information_schema.columns as c
-		where table_name=$1 and table_schema = 'public';
-	`, tableName)
+		where table_name=$1 and table_schema = $2;
+	`, tableName, schema)
 
 	if err != nil {
 		return nil, err
@@ -148,10 +268,11 @@ func (p *PostgresDriver) Columns(tableName string) ([]bdb.Column, error) {
 	defer rows.Close()
 
 	for rows.Next() {
-		var colName, colType, colDefault, nullable string
-		var unique bool
+		var colName, colType, udtName, colDefault, nullable string
+		var unique, ginIndexed bool
 		var defaultPtr *string
-		if err := rows.Scan(&colName, &colType, &defaultPtr, &nullable, &unique); err != nil {
+		var enumValues pq.StringArray
+		if err := rows.Scan(&colName, &colType, &udtName, &defaultPtr, &nullable, &unique, &ginIndexed, &enumValues); err != nil {
 			return nil, errors.Wrapf(err, "unable to scan for table %s", tableName)
 		}
 
@@ -162,12 +283,18 @@ func (p *PostgresDriver) Columns(tableName string) ([]bdb.Column, error) {
 		}
 
 		column := bdb.Column{
-			Name:      colName,
-			DBType:    colType,
-			Default:   colDefault,
-			Nullable:  nullable == "YES",
-			Unique:    unique,
-			Validated: isValidated(colType),
+			Name:            colName,
+			DBType:          colType,
+			UDTName:         udtName,
+			Default:         colDefault,
+			Nullable:        nullable == "YES",
+			Unique:          unique,
+			Validated:       isValidated(colType),
+			FullText:        colType == "tsvector",
+			FullTextIndexed: colType == "tsvector" && ginIndexed,
+			ArrType:         colType == "ARRAY",
+			IsEnum:          len(enumValues) > 0,
+			EnumValues:      []string(enumValues),
 		}
 		columns = append(columns, column)
 	}
@@ -175,17 +302,17 @@ func (p *PostgresDriver) Columns(tableName string) ([]bdb.Column, error) {
 	return columns, nil
 }
 
-// PrimaryKeyInfo looks up the primary key for a table.
-func (p *PostgresDriver) PrimaryKeyInfo(tableName string) (*bdb.PrimaryKey, error) {
+// PrimaryKeyInfo looks up the primary key for a schema-qualified table.
+func (p *PostgresDriver) PrimaryKeyInfo(ctx context.Context, schema, tableName string) (*bdb.PrimaryKey, error) {
 	pkey := &bdb.PrimaryKey{}
 	var err error
 
 	query := `
 	select tc.constraint_name
 	from information_schema.table_constraints as tc
-	where tc.table_name = $1 and tc.constraint_type = 'PRIMARY KEY' and tc.table_schema = 'public';`
+	where tc.table_name = $1 and tc.constraint_type = 'PRIMARY KEY' and tc.table_schema = $2;`
 
-	row := p.dbConn.QueryRow(query, tableName)
+	row := p.dbConn.QueryRowContext(ctx, query, tableName, schema)
 	if err = row.Scan(&pkey.Name); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -196,10 +323,10 @@ func (p *PostgresDriver) PrimaryKeyInfo(tableName string) (*bdb.PrimaryKey, erro
 	queryColumns := `
 	select kcu.column_name
 	from   information_schema.key_column_usage as kcu
-	where  constraint_name = $1 and table_schema = 'public';`
+	where  constraint_name = $1 and table_schema = $2;`
 
 	var rows *sql.Rows
-	if rows, err = p.dbConn.Query(queryColumns, pkey.Name); err != nil {
+	if rows, err = p.dbConn.QueryContext(ctx, queryColumns, pkey.Name, schema); err != nil {
 		return nil, err
 	}
 	defer rows.Close()
@@ -225,8 +352,8 @@ func (p *PostgresDriver) PrimaryKeyInfo(tableName string) (*bdb.PrimaryKey, erro
 	return pkey, nil
 }
 
-// ForeignKeyInfo retrieves the foreign keys for a given table name.
-func (p *PostgresDriver) ForeignKeyInfo(tableName string) ([]bdb.ForeignKey, error) {
+// ForeignKeyInfo retrieves the foreign keys for a schema-qualified table.
+func (p *PostgresDriver) ForeignKeyInfo(ctx context.Context, schema, tableName string) ([]bdb.ForeignKey, error) {
 	var fkeys []bdb.ForeignKey
 
 	query := `
@@ -235,15 +362,16 @@ func (p *PostgresDriver) ForeignKeyInfo(tableName string) ([]bdb.ForeignKey, err
 		kcu.table_name as source_table,
 		kcu.column_name as source_column,
 		ccu.table_name as dest_table,
-		ccu.column_name as dest_column
+		ccu.column_name as dest_column,
+		ccu.table_schema as dest_schema
 	from information_schema.table_constraints as tc
 		inner join information_schema.key_column_usage as kcu ON tc.constraint_name = kcu.constraint_name
 		inner join information_schema.constraint_column_usage as ccu ON tc.constraint_name = ccu.constraint_name
-	where tc.table_name = $1 and tc.constraint_type = 'FOREIGN KEY' and tc.table_schema = 'public';`
+	where tc.table_name = $1 and tc.constraint_type = 'FOREIGN KEY' and tc.table_schema = $2;`
 
 	var rows *sql.Rows
 	var err error
-	if rows, err = p.dbConn.Query(query, tableName); err != nil {
+	if rows, err = p.dbConn.QueryContext(ctx, query, tableName, schema); err != nil {
 		return nil, err
 	}
 
@@ -252,7 +380,7 @@ func (p *PostgresDriver) ForeignKeyInfo(tableName string) ([]bdb.ForeignKey, err
 		var sourceTable string
 
 		fkey.Table = tableName
-		err = rows.Scan(&fkey.Name, &sourceTable, &fkey.Column, &fkey.ForeignTable, &fkey.ForeignColumn)
+		err = rows.Scan(&fkey.Name, &sourceTable, &fkey.Column, &fkey.ForeignTable, &fkey.ForeignColumn, &fkey.ForeignSchema)
 		if err != nil {
 			return nil, err
 		}
@@ -267,11 +395,58 @@ func (p *PostgresDriver) ForeignKeyInfo(tableName string) ([]bdb.ForeignKey, err
 	return fkeys, nil
 }
 
+// arrayGoType maps the udt_name of a postgres array column (always
+// prefixed with an underscore, e.g. "_int4") to the pq typed-array
+// wrapper used to scan/value it. Types without a dedicated pq wrapper
+// fall back to pq.StringArray, which round-trips through the array's
+// text representation.
+func arrayGoType(udtName string) string {
+	switch udtName {
+	case "_int2", "_int4", "_int8":
+		return "pq.Int64Array"
+	case "_float4", "_float8", "_numeric":
+		return "pq.Float64Array"
+	case "_bool":
+		return "pq.BoolArray"
+	case "_bytea":
+		return "pq.ByteaArray"
+	default:
+		return "pq.StringArray"
+	}
+}
+
+// rangeGoType maps a postgres range type to the name of its bdb/types
+// wrapper, e.g. "int4range" -> "Int4Range".
+func rangeGoType(dbType string) string {
+	return strmangle.TitleCase(strings.TrimSuffix(dbType, "range")) + "Range"
+}
+
+// enumGoType returns the name of the generated Go typed-string for a
+// Postgres enum, e.g. "user_status" -> "UserStatus". Only the name is
+// produced; no declaration of the type, its constants, or Scan/Value
+// methods is generated by this package.
+func enumGoType(udtName string) string {
+	return strmangle.TitleCase(udtName)
+}
+
 // TranslateColumnType converts postgres database types to Go types, for example
 // "varchar" to "string" and "bigint" to "int64". It returns this parsed data
 // as a Column object.
 func (p *PostgresDriver) TranslateColumnType(c bdb.Column) bdb.Column {
 	if c.Nullable {
+		switch {
+		case c.IsEnum:
+			// The enum's Go type name (see enumGoType) gets a null.*
+			// prefix like every other nullable non-primitive.
+			c.Type = "null." + enumGoType(c.UDTName)
+			return c
+		case c.ArrType:
+			// pq's typed array wrappers already scan/value SQL NULL as
+			// a nil slice, so no null.* wrapper is needed here.
+			c.Type = arrayGoType(c.UDTName)
+			return c
+		}
+
 		switch c.DBType {
 		case "bigint", "bigserial":
 			c.Type = "null.Int64"
@@ -283,7 +458,7 @@ func (p *PostgresDriver) TranslateColumnType(c bdb.Column) bdb.Column {
 			c.Type = "null.Float64"
 		case "real":
 			c.Type = "null.Float32"
-		case "bit", "interval", "bit varying", "character", "character varying", "cidr", "inet", "json", "macaddr", "text", "uuid", "xml":
+		case "bit", "interval", "bit varying", "character", "character varying", "cidr", "inet", "macaddr", "text", "uuid", "xml":
 			c.Type = "null.String"
 		case "bytea":
 			c.Type = "[]byte"
@@ -291,10 +466,25 @@ func (p *PostgresDriver) TranslateColumnType(c bdb.Column) bdb.Column {
 			c.Type = "null.Bool"
 		case "date", "time", "timestamp without time zone", "timestamp with time zone":
 			c.Type = "null.Time"
+		case "json", "jsonb":
+			c.Type = "null.JSON"
+		case "hstore":
+			c.Type = "null.Hstore"
+		case "int4range", "int8range", "numrange", "tsrange", "tstzrange", "daterange":
+			c.Type = "null." + rangeGoType(c.DBType)
 		default:
 			c.Type = "null.String"
 		}
 	} else {
+		switch {
+		case c.IsEnum:
+			c.Type = enumGoType(c.UDTName)
+			return c
+		case c.ArrType:
+			c.Type = arrayGoType(c.UDTName)
+			return c
+		}
+
 		switch c.DBType {
 		case "bigint", "bigserial":
 			c.Type = "int64"
@@ -306,12 +496,18 @@ func (p *PostgresDriver) TranslateColumnType(c bdb.Column) bdb.Column {
 			c.Type = "float64"
 		case "real":
 			c.Type = "float32"
-		case "bit", "interval", "uuint", "bit varying", "character", "character varying", "cidr", "inet", "json", "macaddr", "text", "uuid", "xml":
+		case "bit", "interval", "uuint", "bit varying", "character", "character varying", "cidr", "inet", "macaddr", "text", "uuid", "xml":
 			c.Type = "string"
 		case "bytea":
 			c.Type = "[]byte"
 		case "boolean":
 			c.Type = "bool"
+		case "json", "jsonb":
+			c.Type = "types.JSON"
+		case "hstore":
+			c.Type = "hstore.Hstore"
+		case "int4range", "int8range", "numrange", "tsrange", "tstzrange", "daterange":
+			c.Type = "types." + rangeGoType(c.DBType)
 		case "date", "time", "timestamp without time zone", "timestamp with time zone":
 			c.Type = "time.Time"
 		default:
@@ -322,6 +518,35 @@ func (p *PostgresDriver) TranslateColumnType(c bdb.Column) bdb.Column {
 	return c
 }
 
+// TableInfo assembles a fully-populated bdb.Table for schema.tableName:
+// its columns, primary key, foreign keys, and Kind. Kind is read by the
+// migrate package's diff engine, which skips anything that isn't a
+// plain "table" (a view, materialized view, or foreign table) since
+// none of those can be the target of CREATE/ALTER/DROP TABLE. This
+// snapshot tree has no model-generation template pipeline of its own,
+// so Kind only has the one real consumer today; a future generator
+// would read it the same way to decide whether to emit Insert/Update/
+// Delete/upsert for a given table.
+func (p *PostgresDriver) TableInfo(ctx context.Context, schema, tableName string) (bdb.Table, error) {
+	table := bdb.Table{Name: tableName, Schema: schema}
+	var err error
+
+	if table.Kind, err = p.TableKind(ctx, schema, tableName); err != nil {
+		return bdb.Table{}, err
+	}
+	if table.Columns, err = p.Columns(ctx, schema, tableName); err != nil {
+		return bdb.Table{}, err
+	}
+	if table.PKey, err = p.PrimaryKeyInfo(ctx, schema, tableName); err != nil {
+		return bdb.Table{}, err
+	}
+	if table.FKeys, err = p.ForeignKeyInfo(ctx, schema, tableName); err != nil {
+		return bdb.Table{}, err
+	}
+
+	return table, nil
+}
+
 // isValidated checks if the database type is in the validatedTypes list.
 func isValidated(typ string) bool {
 	for _, v := range validatedTypes {
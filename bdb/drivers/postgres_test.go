@@ -0,0 +1,79 @@
+package drivers
+
+import (
+	"testing"
+
+	"github.com/vattle/sqlboiler/bdb"
+)
+
+func TestTranslateColumnTypeNullable(t *testing.T) {
+	p := &PostgresDriver{}
+
+	tests := []struct {
+		name string
+		col  bdb.Column
+		want string
+	}{
+		{"non-null enum", bdb.Column{IsEnum: true, UDTName: "user_status"}, "UserStatus"},
+		{"null enum", bdb.Column{IsEnum: true, UDTName: "user_status", Nullable: true}, "null.UserStatus"},
+		{"non-null array", bdb.Column{ArrType: true, UDTName: "_int4"}, "pq.Int64Array"},
+		{"null array", bdb.Column{ArrType: true, UDTName: "_int4", Nullable: true}, "pq.Int64Array"},
+		{"non-null int", bdb.Column{DBType: "integer"}, "int"},
+		{"null int", bdb.Column{DBType: "integer", Nullable: true}, "null.Int"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := p.TranslateColumnType(tt.col)
+			if got.Type != tt.want {
+				t.Errorf("TranslateColumnType(%+v).Type = %q, want %q", tt.col, got.Type, tt.want)
+			}
+		})
+	}
+}
+
+func TestArrayGoType(t *testing.T) {
+	tests := map[string]string{
+		"_int4":    "pq.Int64Array",
+		"_float8":  "pq.Float64Array",
+		"_bool":    "pq.BoolArray",
+		"_bytea":   "pq.ByteaArray",
+		"_varchar": "pq.StringArray",
+	}
+
+	for udtName, want := range tests {
+		if got := arrayGoType(udtName); got != want {
+			t.Errorf("arrayGoType(%q) = %q, want %q", udtName, got, want)
+		}
+	}
+}
+
+func TestEnumGoType(t *testing.T) {
+	if got, want := enumGoType("user_status"), "UserStatus"; got != want {
+		t.Errorf("enumGoType(%q) = %q, want %q", "user_status", got, want)
+	}
+}
+
+func TestRangeGoType(t *testing.T) {
+	if got, want := rangeGoType("int4range"), "Int4Range"; got != want {
+		t.Errorf("rangeGoType(%q) = %q, want %q", "int4range", got, want)
+	}
+}
+
+func TestPackageNameFor(t *testing.T) {
+	p := &PostgresDriver{}
+	table := bdb.Table{Name: "users", Schema: "auth"}
+
+	if got, want := p.PackageNameFor(table, "models"), "models"; got != want {
+		t.Errorf("PackageNameFor with no schema split = %q, want %q", got, want)
+	}
+
+	p.SetPackagePerSchema(true)
+	if got, want := p.PackageNameFor(table, "models"), "auth"; got != want {
+		t.Errorf("PackageNameFor with schema split = %q, want %q", got, want)
+	}
+
+	if got, want := p.PackageNameFor(bdb.Table{Name: "widgets"}, "models"), "models"; got != want {
+		t.Errorf("PackageNameFor for a table with no schema should fall back to defaultPkg, got %q, want %q", got, want)
+	}
+}
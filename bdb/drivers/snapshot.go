@@ -0,0 +1,104 @@
+package drivers
+
+import (
+	"context"
+
+	"github.com/vattle/sqlboiler/bdb"
+)
+
+// Index describes a single index found on a table, enough to detect
+// whether a migration needs to add or drop one.
+type Index struct {
+	Name    string
+	Table   string
+	Columns []string
+	Unique  bool
+}
+
+// SchemaSnapshot is a point-in-time capture of a schema: every table's
+// columns, primary key, foreign keys, and indexes. It is produced by
+// Snapshot and can be persisted as JSON to compare a database against a
+// previous run. The migration diff compares tables, columns, and
+// foreign keys; Indexes are captured but not compared, and no snapshot
+// is derived from generated models.
+type SchemaSnapshot struct {
+	Tables  []bdb.Table
+	Indexes []Index
+}
+
+// IndexInfo looks up every index defined on a schema-qualified table.
+func (p *PostgresDriver) IndexInfo(ctx context.Context, schema, tableName string) ([]Index, error) {
+	var indexes []Index
+
+	query := `
+	select pgix.indexname, pga.attname, pgi.indisunique
+	from pg_indexes pgix
+		inner join pg_class pgc on pgix.indexname = pgc.relname and pgc.relkind = 'i'
+		inner join pg_index pgi on pgi.indexrelid = pgc.oid
+		inner join pg_attribute pga on pga.attrelid = pgi.indrelid and pga.attnum = ANY(pgi.indkey)
+	where pgix.schemaname = $1 and pgix.tablename = $2;`
+
+	rows, err := p.dbConn.QueryContext(ctx, query, schema, tableName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	byName := map[string]*Index{}
+	var order []string
+	for rows.Next() {
+		var name, column string
+		var unique bool
+		if err := rows.Scan(&name, &column, &unique); err != nil {
+			return nil, err
+		}
+
+		idx, ok := byName[name]
+		if !ok {
+			idx = &Index{Name: name, Table: tableName, Unique: unique}
+			byName[name] = idx
+			order = append(order, name)
+		}
+		idx.Columns = append(idx.Columns, column)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range order {
+		indexes = append(indexes, *byName[name])
+	}
+
+	return indexes, nil
+}
+
+// Snapshot walks every table in p.schemas and captures its columns,
+// primary key, foreign keys, and indexes into a SchemaSnapshot that can
+// be diffed against a saved snapshot.
+func (p *PostgresDriver) Snapshot(ctx context.Context, exclude []string) (*SchemaSnapshot, error) {
+	names, err := p.TableNames(ctx, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	snap := &SchemaSnapshot{}
+	for _, ident := range names {
+		table, err := p.TableInfo(ctx, ident.Schema, ident.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		indexes, err := p.IndexInfo(ctx, ident.Schema, ident.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		snap.Tables = append(snap.Tables, table)
+		snap.Indexes = append(snap.Indexes, indexes...)
+	}
+
+	snap.Tables = bdb.DetectJoinTables(snap.Tables)
+
+	return snap, nil
+}
@@ -0,0 +1,16 @@
+package bdb
+
+// ForeignKey represents a foreign key constraint on a table.
+type ForeignKey struct {
+	Table    string
+	Name     string
+	Column   string
+	Nullable bool
+	Unique   bool
+
+	ForeignTable          string
+	ForeignSchema         string
+	ForeignColumn         string
+	ForeignColumnNullable bool
+	ForeignColumnUnique   bool
+}
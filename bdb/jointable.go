@@ -0,0 +1,62 @@
+package bdb
+
+// DetectJoinTables scans tables for many-to-many join tables that carry
+// columns beyond their two foreign keys (e.g. a "role" or
+// "royalty_split" column on the join table itself), marks them
+// IsJoinTable, and adds the ToJoinTable ToManyRelationship each side
+// needs to traverse through the join table. Plain two-FK join tables
+// with no extra columns are left alone, since those are already
+// reachable through the ordinary ToManyRelationship traversal without
+// needing a first-class model of their own.
+//
+// It returns tables with IsJoinTable/ToManyRelationships updated in
+// place; the slice itself is reused, not copied.
+func DetectJoinTables(tables []Table) []Table {
+	byName := make(map[string]int, len(tables))
+	for i, t := range tables {
+		byName[t.Name] = i
+	}
+
+	for i := range tables {
+		t := &tables[i]
+		if len(t.FKeys) != 2 || len(t.Columns) <= 2 {
+			continue
+		}
+
+		t.IsJoinTable = true
+
+		fk1, fk2 := t.FKeys[0], t.FKeys[1]
+		addJoinRelationship(tables, byName, t.Name, fk1, fk2)
+		addJoinRelationship(tables, byName, t.Name, fk2, fk1)
+	}
+
+	return tables
+}
+
+// addJoinRelationship adds the ToManyRelationship that lets
+// local.ForeignTable reach foreign.ForeignTable through joinTable, onto
+// local.ForeignTable's own ToManyRelationships. Called once per
+// direction so both sides of the join table get a relationship.
+func addJoinRelationship(tables []Table, byName map[string]int, joinTable string, local, foreign ForeignKey) {
+	idx, ok := byName[local.ForeignTable]
+	if !ok {
+		return
+	}
+
+	tables[idx].ToManyRelationships = append(tables[idx].ToManyRelationships, ToManyRelationship{
+		Column: local.ForeignColumn,
+		Table:  local.ForeignTable,
+		Schema: local.ForeignSchema,
+
+		ForeignTable:          foreign.ForeignTable,
+		ForeignSchema:         foreign.ForeignSchema,
+		ForeignColumn:         foreign.ForeignColumn,
+		ForeignColumnNullable: foreign.ForeignColumnNullable,
+		ForeignColumnUnique:   foreign.ForeignColumnUnique,
+
+		ToJoinTable:       true,
+		JoinTable:         joinTable,
+		JoinLocalColumn:   local.Column,
+		JoinForeignColumn: foreign.Column,
+	})
+}
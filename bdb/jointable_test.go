@@ -0,0 +1,70 @@
+package bdb
+
+import "testing"
+
+func TestDetectJoinTables(t *testing.T) {
+	tables := []Table{
+		{Name: "authors"},
+		{Name: "books"},
+		{
+			Name: "author_books",
+			FKeys: []ForeignKey{
+				{Table: "author_books", Column: "author_id", ForeignTable: "authors", ForeignColumn: "id"},
+				{Table: "author_books", Column: "book_id", ForeignTable: "books", ForeignColumn: "id"},
+			},
+			Columns: []Column{
+				{Name: "author_id"},
+				{Name: "book_id"},
+				{Name: "royalty_split"},
+			},
+		},
+	}
+
+	got := DetectJoinTables(tables)
+
+	joinTable := GetTable(got, "", "author_books")
+	if !joinTable.IsJoinTable {
+		t.Fatal("author_books should be detected as a join table")
+	}
+
+	authors := GetTable(got, "", "authors")
+	if len(authors.ToManyRelationships) != 1 {
+		t.Fatalf("authors should get one ToManyRelationship through the join table, got %d", len(authors.ToManyRelationships))
+	}
+	rel := authors.ToManyRelationships[0]
+	if !rel.ToJoinTable || rel.JoinTable != "author_books" || rel.ForeignTable != "books" {
+		t.Errorf("authors -> books relationship wrong: %+v", rel)
+	}
+
+	books := GetTable(got, "", "books")
+	if len(books.ToManyRelationships) != 1 {
+		t.Fatalf("books should get one ToManyRelationship through the join table, got %d", len(books.ToManyRelationships))
+	}
+	if rel := books.ToManyRelationships[0]; !rel.ToJoinTable || rel.JoinTable != "author_books" || rel.ForeignTable != "authors" {
+		t.Errorf("books -> authors relationship wrong: %+v", rel)
+	}
+}
+
+func TestDetectJoinTablesIgnoresPlainJoinTable(t *testing.T) {
+	tables := []Table{
+		{Name: "authors"},
+		{Name: "books"},
+		{
+			Name: "author_books",
+			FKeys: []ForeignKey{
+				{Table: "author_books", Column: "author_id", ForeignTable: "authors", ForeignColumn: "id"},
+				{Table: "author_books", Column: "book_id", ForeignTable: "books", ForeignColumn: "id"},
+			},
+			Columns: []Column{
+				{Name: "author_id"},
+				{Name: "book_id"},
+			},
+		},
+	}
+
+	got := DetectJoinTables(tables)
+
+	if joinTable := GetTable(got, "", "author_books"); joinTable.IsJoinTable {
+		t.Error("a two-FK join table with no extra columns should not be marked IsJoinTable")
+	}
+}
@@ -0,0 +1,7 @@
+package bdb
+
+// PrimaryKey represents a primary key constraint on a table.
+type PrimaryKey struct {
+	Name    string
+	Columns []string
+}
@@ -0,0 +1,26 @@
+package bdb
+
+// ToManyRelationship describes a one-to-many or many-to-many
+// relationship reachable from Table.Column, either directly to
+// ForeignTable.ForeignColumn or, when ToJoinTable is true, through
+// JoinTable (whose JoinLocalColumn/JoinForeignColumn point back at Table
+// and ForeignTable respectively).
+type ToManyRelationship struct {
+	Column string
+	Table  string
+	Schema string
+
+	ForeignTable          string
+	ForeignSchema         string
+	ForeignColumn         string
+	ForeignColumnNullable bool
+	ForeignColumnUnique   bool
+
+	ToJoinTable       bool
+	JoinTable         string
+	JoinLocalColumn   string
+	JoinForeignColumn string
+
+	Nullable bool
+	Unique   bool
+}
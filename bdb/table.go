@@ -0,0 +1,55 @@
+package bdb
+
+// Table metadata from the database schema.
+type Table struct {
+	Name string
+
+	// Schema is the Postgres schema this table lives in. It prefixes Go
+	// type names when the same table name is introspected out of more
+	// than one schema in a single run, qualifies the table's name in
+	// generated migration SQL, and picks the package when models are
+	// split per schema. An empty Schema leaves names unqualified.
+	Schema string
+
+	// Kind is "table", "view", "materialized_view", or "foreign_table".
+	// The migration diff engine emits no DDL for anything other than
+	// "table". No Insert/Update/Delete or upsert suppression is done
+	// based on it here.
+	Kind string
+
+	Columns []Column
+	PKey    *PrimaryKey
+	FKeys   []ForeignKey
+
+	IsJoinTable         bool
+	ToManyRelationships []ToManyRelationship
+}
+
+// GetColumn returns the column on the table with the given name, or a
+// zero Column if there isn't one.
+func (t Table) GetColumn(name string) Column {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c
+		}
+	}
+
+	return Column{}
+}
+
+// GetTable finds the table named name in schema in tables, or a zero
+// Table if there isn't one. schema may be left empty by callers that
+// have no schema to disambiguate with (e.g. a single-schema run), in
+// which case the first table with a matching name wins, same as
+// before schemas existed. Callers that do have a schema on hand (a
+// ForeignKey's ForeignSchema, a ToManyRelationship's Schema) should
+// always pass it, since two schemas may introspect a same-named table.
+func GetTable(tables []Table, schema, name string) Table {
+	for _, t := range tables {
+		if t.Name == name && (schema == "" || t.Schema == schema) {
+			return t
+		}
+	}
+
+	return Table{}
+}
@@ -0,0 +1,123 @@
+// Package types houses the Go types used to represent Postgres values
+// that don't map cleanly onto anything in the standard library, such as
+// jsonb and range types. Generated models reference these directly, and
+// each one implements database/sql.Scanner and driver.Valuer so that it
+// can be used as a struct field with database/sql.
+package types
+
+import (
+	"database/sql/driver"
+
+	"github.com/pkg/errors"
+)
+
+// JSON wraps the raw bytes of a Postgres json/jsonb column so callers
+// can further unmarshal it into their own type without sqlboiler having
+// to know its shape.
+type JSON []byte
+
+// Scan implements the sql.Scanner interface.
+func (j *JSON) Scan(value interface{}) error {
+	if value == nil {
+		*j = nil
+		return nil
+	}
+
+	switch t := value.(type) {
+	case []byte:
+		*j = append((*j)[0:0], t...)
+	case string:
+		*j = JSON(t)
+	default:
+		return errors.Errorf("unable to scan type %T into JSON", value)
+	}
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (j JSON) Value() (driver.Value, error) {
+	if len(j) == 0 {
+		return nil, nil
+	}
+
+	return []byte(j), nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (j JSON) MarshalJSON() ([]byte, error) {
+	if len(j) == 0 {
+		return []byte("null"), nil
+	}
+
+	return j, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (j *JSON) UnmarshalJSON(data []byte) error {
+	if j == nil {
+		return errors.New("types.JSON: UnmarshalJSON on nil pointer")
+	}
+
+	*j = append((*j)[0:0], data...)
+	return nil
+}
+
+// Range is a generic representation of a Postgres range type, stored in
+// its canonical text form (e.g. "[1,10)"). Range-specific types such as
+// Int4Range embed it so each still satisfies a distinct Go type for the
+// generated model field.
+type Range struct {
+	Bounds string
+}
+
+// Scan implements the sql.Scanner interface.
+func (r *Range) Scan(value interface{}) error {
+	if value == nil {
+		r.Bounds = ""
+		return nil
+	}
+
+	switch t := value.(type) {
+	case []byte:
+		r.Bounds = string(t)
+	case string:
+		r.Bounds = t
+	default:
+		return errors.Errorf("unable to scan type %T into Range", value)
+	}
+
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (r Range) Value() (driver.Value, error) {
+	if r.Bounds == "" {
+		return nil, nil
+	}
+
+	return r.Bounds, nil
+}
+
+// String implements fmt.Stringer.
+func (r Range) String() string {
+	return r.Bounds
+}
+
+// Int4Range wraps a Postgres int4range column.
+type Int4Range struct{ Range }
+
+// Int8Range wraps a Postgres int8range column.
+type Int8Range struct{ Range }
+
+// NumRange wraps a Postgres numrange column.
+type NumRange struct{ Range }
+
+// TsRange wraps a Postgres tsrange column.
+type TsRange struct{ Range }
+
+// TstzRange wraps a Postgres tstzrange column.
+type TstzRange struct{ Range }
+
+// DateRange wraps a Postgres daterange column.
+type DateRange struct{ Range }
@@ -0,0 +1,434 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vattle/sqlboiler/bdb"
+	"github.com/vattle/sqlboiler/bdb/drivers"
+)
+
+// MigrationStep is a single schema change detected by diffSnapshots. Up
+// is the SQL that applies it, Down is the SQL that reverts it.
+type MigrationStep struct {
+	Up   string
+	Down string
+}
+
+// tableKey identifies a table by schema and name so that two tables
+// with the same name in different schemas are never confused for one
+// another while diffing.
+type tableKey struct {
+	Schema string
+	Name   string
+}
+
+// qualifiedName schema-qualifies name when schema is set, so objects in
+// a non-default schema always get an unambiguous SQL identifier. Tables
+// with no schema (the common single-schema case) are left bare to keep
+// existing single-schema migrations unchanged.
+func qualifiedName(schema, name string) string {
+	if schema == "" {
+		return name
+	}
+
+	return fmt.Sprintf("%s.%s", schema, name)
+}
+
+// diffSnapshots compares a previous schema snapshot against the current
+// one and returns the ordered steps needed to bring "from" up to "to":
+// added/dropped/renamed tables, added/dropped/renamed/type-changed
+// columns, and added/dropped foreign keys. Tables are matched by schema
+// and name together, so same-named tables in different schemas are
+// never confused for one another. Table and column order is sorted so
+// the same pair of snapshots always produces the same steps in the same
+// order.
+func diffSnapshots(from, to *drivers.SchemaSnapshot) []MigrationStep {
+	var steps []MigrationStep
+
+	fromTables := tablesByName(onlyTables(from.Tables))
+	toTables := tablesByName(onlyTables(to.Tables))
+	renamedTables := renamedTableNames(fromTables, toTables)
+
+	for _, key := range sortedKeys(toTables) {
+		table := toTables[key]
+
+		if oldKey, ok := renamedTables[key]; ok {
+			steps = append(steps, MigrationStep{
+				Up:   fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", qualifiedName(oldKey.Schema, oldKey.Name), key.Name),
+				Down: fmt.Sprintf("ALTER TABLE %s RENAME TO %s;", qualifiedName(key.Schema, key.Name), oldKey.Name),
+			})
+			steps = append(steps, diffColumns(fromTables[oldKey], table)...)
+			steps = append(steps, diffForeignKeys(fromTables[oldKey], table)...)
+			continue
+		}
+
+		if _, ok := fromTables[key]; !ok {
+			steps = append(steps, MigrationStep{
+				Up:   createTableSQL(table),
+				Down: fmt.Sprintf("DROP TABLE %s;", qualifiedName(key.Schema, key.Name)),
+			})
+			continue
+		}
+
+		steps = append(steps, diffColumns(fromTables[key], table)...)
+		steps = append(steps, diffForeignKeys(fromTables[key], table)...)
+	}
+
+	renamedAway := map[tableKey]bool{}
+	for _, oldKey := range renamedTables {
+		renamedAway[oldKey] = true
+	}
+
+	for _, key := range sortedKeys(fromTables) {
+		if renamedAway[key] {
+			continue
+		}
+		if _, ok := toTables[key]; !ok {
+			steps = append(steps, MigrationStep{
+				Up:   fmt.Sprintf("DROP TABLE %s;", qualifiedName(key.Schema, key.Name)),
+				Down: createTableSQL(fromTables[key]),
+			})
+		}
+	}
+
+	return steps
+}
+
+// renamedTableNames detects table renames between two snapshots: a
+// table missing from "from" (by key) and a table missing from "to" are
+// treated as a rename when their column sets are identical, since a
+// plain drop+add would otherwise be indistinguishable from a rename in
+// the common case of "nothing about the table changed but its name".
+// Renames are only matched within the same schema; a table reappearing
+// under the same name in a different schema is an unrelated table, not
+// a rename. It returns a map of new key -> old key.
+func renamedTableNames(fromTables, toTables map[tableKey]bdb.Table) map[tableKey]tableKey {
+	renamed := map[tableKey]tableKey{}
+
+	var dropped []tableKey
+	for key := range fromTables {
+		if _, ok := toTables[key]; !ok {
+			dropped = append(dropped, key)
+		}
+	}
+	sort.Slice(dropped, func(i, j int) bool { return lessKey(dropped[i], dropped[j]) })
+
+	var added []tableKey
+	for key := range toTables {
+		if _, ok := fromTables[key]; !ok {
+			added = append(added, key)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return lessKey(added[i], added[j]) })
+
+	used := map[tableKey]bool{}
+	for _, addedKey := range added {
+		for _, droppedKey := range dropped {
+			if used[droppedKey] || droppedKey.Schema != addedKey.Schema {
+				continue
+			}
+			if sameColumnSignature(fromTables[droppedKey], toTables[addedKey]) {
+				renamed[addedKey] = droppedKey
+				used[droppedKey] = true
+				break
+			}
+		}
+	}
+
+	return renamed
+}
+
+// sameColumnSignature reports whether two tables have identical column
+// name/type pairs, regardless of order.
+func sameColumnSignature(a, b bdb.Table) bool {
+	if len(a.Columns) != len(b.Columns) {
+		return false
+	}
+
+	aCols := columnsByName(a.Columns)
+	bCols := columnsByName(b.Columns)
+	for name, col := range aCols {
+		other, ok := bCols[name]
+		if !ok || other.DBType != col.DBType {
+			return false
+		}
+	}
+
+	return true
+}
+
+// onlyTables drops views, materialized views, and foreign tables from
+// tables, since none of those can be targeted by CREATE/ALTER/DROP
+// TABLE: table.Kind is what tells this diff engine which entries are
+// real tables it's allowed to emit DDL for. A table with no Kind set
+// (Kind == "") is treated as a plain table, so SchemaSnapshots built by
+// hand (as the tests in this package do) keep working without having
+// to set it.
+func onlyTables(tables []bdb.Table) []bdb.Table {
+	var out []bdb.Table
+	for _, t := range tables {
+		if t.Kind == "" || t.Kind == "table" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+func tablesByName(tables []bdb.Table) map[tableKey]bdb.Table {
+	m := make(map[tableKey]bdb.Table, len(tables))
+	for _, t := range tables {
+		m[tableKey{Schema: t.Schema, Name: t.Name}] = t
+	}
+	return m
+}
+
+// lessKey orders tableKeys by schema then name, so iteration order is
+// deterministic regardless of map order.
+func lessKey(a, b tableKey) bool {
+	if a.Schema != b.Schema {
+		return a.Schema < b.Schema
+	}
+	return a.Name < b.Name
+}
+
+// sortedKeys returns the keys of a map[tableKey]bdb.Table in sorted
+// order, so callers that range over it get deterministic output.
+func sortedKeys(tables map[tableKey]bdb.Table) []tableKey {
+	keys := make([]tableKey, 0, len(tables))
+	for k := range tables {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return lessKey(keys[i], keys[j]) })
+	return keys
+}
+
+// diffColumns detects added, dropped, renamed, and type-changed columns
+// between the same table in two snapshots. A dropped column and an
+// added column are treated as a rename when they share a DBType, since
+// a type-preserving rename is by far the common case and otherwise
+// always shows up as a drop plus an add.
+func diffColumns(from, to bdb.Table) []MigrationStep {
+	var steps []MigrationStep
+
+	toName := qualifiedName(to.Schema, to.Name)
+	fromName := qualifiedName(from.Schema, from.Name)
+
+	fromCols := columnsByName(from.Columns)
+	toCols := columnsByName(to.Columns)
+
+	var dropped, added []string
+	for name := range fromCols {
+		if _, ok := toCols[name]; !ok {
+			dropped = append(dropped, name)
+		}
+	}
+	for name := range toCols {
+		if _, ok := fromCols[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(dropped)
+	sort.Strings(added)
+
+	renamed := map[string]string{} // new name -> old name
+	used := map[string]bool{}
+	for _, newName := range added {
+		for _, oldName := range dropped {
+			if used[oldName] {
+				continue
+			}
+			if fromCols[oldName].DBType == toCols[newName].DBType {
+				renamed[newName] = oldName
+				used[oldName] = true
+				break
+			}
+		}
+	}
+
+	for _, name := range sortedColumnKeys(toCols) {
+		col := toCols[name]
+
+		if oldName, ok := renamed[name]; ok {
+			steps = append(steps, MigrationStep{
+				Up:   fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", toName, oldName, name),
+				Down: fmt.Sprintf("ALTER TABLE %s RENAME COLUMN %s TO %s;", toName, name, oldName),
+			})
+			continue
+		}
+
+		existing, ok := fromCols[name]
+		switch {
+		case !ok:
+			steps = append(steps, MigrationStep{
+				Up:   fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", toName, name, col.DBType),
+				Down: fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", toName, name),
+			})
+		case existing.DBType != col.DBType:
+			steps = append(steps, MigrationStep{
+				Up: fmt.Sprintf(
+					"ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;",
+					toName, name, col.DBType, name, col.DBType,
+				),
+				Down: fmt.Sprintf(
+					"ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s::%s;",
+					toName, name, existing.DBType, name, existing.DBType,
+				),
+			})
+		}
+	}
+
+	for _, name := range sortedColumnKeys(fromCols) {
+		if used[name] {
+			continue
+		}
+		if _, ok := toCols[name]; !ok {
+			col := fromCols[name]
+			steps = append(steps, MigrationStep{
+				Up:   fmt.Sprintf("ALTER TABLE %s DROP COLUMN %s;", fromName, name),
+				Down: fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", fromName, name, col.DBType),
+			})
+		}
+	}
+
+	return steps
+}
+
+// diffForeignKeys detects foreign keys added or dropped between the
+// same table in two snapshots, keyed by constraint name.
+func diffForeignKeys(from, to bdb.Table) []MigrationStep {
+	var steps []MigrationStep
+
+	fromFKeys := fkeysByName(from.FKeys)
+	toFKeys := fkeysByName(to.FKeys)
+
+	for _, name := range sortedFKeyKeys(toFKeys) {
+		if _, ok := fromFKeys[name]; !ok {
+			fkey := toFKeys[name]
+			steps = append(steps, MigrationStep{
+				Up:   addForeignKeySQL(to, fkey),
+				Down: fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", qualifiedName(to.Schema, to.Name), fkey.Name),
+			})
+		}
+	}
+
+	for _, name := range sortedFKeyKeys(fromFKeys) {
+		if _, ok := toFKeys[name]; !ok {
+			fkey := fromFKeys[name]
+			steps = append(steps, MigrationStep{
+				Up:   fmt.Sprintf("ALTER TABLE %s DROP CONSTRAINT %s;", qualifiedName(from.Schema, from.Name), fkey.Name),
+				Down: addForeignKeySQL(from, fkey),
+			})
+		}
+	}
+
+	return steps
+}
+
+// addForeignKeySQL renders the ALTER TABLE ... ADD CONSTRAINT statement
+// for fkey, which lives on table. Both table and the referenced table
+// are schema-qualified when they carry a schema, since fkey.ForeignTable
+// may live in a different schema than table itself.
+func addForeignKeySQL(table bdb.Table, fkey bdb.ForeignKey) string {
+	return fmt.Sprintf(
+		"ALTER TABLE %s ADD CONSTRAINT %s FOREIGN KEY (%s) REFERENCES %s (%s);",
+		qualifiedName(table.Schema, table.Name), fkey.Name, fkey.Column,
+		qualifiedName(fkey.ForeignSchema, fkey.ForeignTable), fkey.ForeignColumn,
+	)
+}
+
+func fkeysByName(fkeys []bdb.ForeignKey) map[string]bdb.ForeignKey {
+	m := make(map[string]bdb.ForeignKey, len(fkeys))
+	for _, fk := range fkeys {
+		m[fk.Name] = fk
+	}
+	return m
+}
+
+func sortedFKeyKeys(fkeys map[string]bdb.ForeignKey) []string {
+	keys := make([]string, 0, len(fkeys))
+	for k := range fkeys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func columnsByName(cols []bdb.Column) map[string]bdb.Column {
+	m := make(map[string]bdb.Column, len(cols))
+	for _, c := range cols {
+		m[c.Name] = c
+	}
+	return m
+}
+
+func sortedColumnKeys(cols map[string]bdb.Column) []string {
+	keys := make([]string, 0, len(cols))
+	for k := range cols {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// createTableSQL renders a best-effort CREATE TABLE statement for a
+// table that only exists in the target snapshot. It emits column names
+// and types only: primary keys, foreign keys, and NOT NULL are not
+// written, and diffSnapshots emits no ALTER TABLE for them when a
+// table is added.
+func createTableSQL(table bdb.Table) string {
+	defs := make([]string, len(table.Columns))
+	for i, col := range table.Columns {
+		defs[i] = fmt.Sprintf("%s %s", col.Name, col.DBType)
+	}
+
+	return fmt.Sprintf("CREATE TABLE %s (\n\t%s\n);", qualifiedName(table.Schema, table.Name), strings.Join(defs, ",\n\t"))
+}
+
+// migrationFilenames returns the goose/golang-migrate-compatible
+// up/down filenames for a migration, e.g.
+// "20060102150405_add_users_email.up.sql" and the matching ".down.sql".
+func migrationFilenames(timestamp, name string) (up, down string) {
+	slug := strings.Replace(strings.ToLower(name), " ", "_", -1)
+	base := fmt.Sprintf("%s_%s", timestamp, slug)
+	return base + ".up.sql", base + ".down.sql"
+}
+
+// renderMigration joins a set of steps into the up and down SQL bodies
+// for a single migration file. Down steps are emitted in reverse order
+// so that reverting always undoes the most recent change first.
+func renderMigration(steps []MigrationStep) (up, down string) {
+	ups := make([]string, len(steps))
+	downs := make([]string, len(steps))
+	for i, step := range steps {
+		ups[i] = step.Up
+		downs[len(steps)-1-i] = step.Down
+	}
+
+	return strings.Join(ups, "\n"), strings.Join(downs, "\n")
+}
+
+// GenerateMigration diffs from against to and writes the resulting
+// up/down SQL to <outDir>/<timestamp>_<name>.up.sql and the matching
+// .down.sql, returning the paths it wrote. No "migrate" command-line
+// subcommand calls it.
+func GenerateMigration(from, to *drivers.SchemaSnapshot, timestamp, name, outDir string) (upPath, downPath string, err error) {
+	steps := diffSnapshots(from, to)
+	up, down := renderMigration(steps)
+	upFile, downFile := migrationFilenames(timestamp, name)
+
+	upPath = filepath.Join(outDir, upFile)
+	downPath = filepath.Join(outDir, downFile)
+
+	if err = ioutil.WriteFile(upPath, []byte(up), 0644); err != nil {
+		return "", "", err
+	}
+	if err = ioutil.WriteFile(downPath, []byte(down), 0644); err != nil {
+		return "", "", err
+	}
+
+	return upPath, downPath, nil
+}
@@ -0,0 +1,213 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vattle/sqlboiler/bdb"
+	"github.com/vattle/sqlboiler/bdb/drivers"
+)
+
+func stepStrings(steps []MigrationStep) (ups, downs []string) {
+	for _, s := range steps {
+		ups = append(ups, s.Up)
+		downs = append(downs, s.Down)
+	}
+	return ups, downs
+}
+
+func TestDiffSnapshotsAddDropTable(t *testing.T) {
+	from := &drivers.SchemaSnapshot{
+		Tables: []bdb.Table{{Name: "authors", Columns: []bdb.Column{{Name: "id", DBType: "integer"}}}},
+	}
+	to := &drivers.SchemaSnapshot{
+		Tables: []bdb.Table{{Name: "books", Columns: []bdb.Column{{Name: "isbn", DBType: "text"}}}},
+	}
+
+	ups, _ := stepStrings(diffSnapshots(from, to))
+	joined := strings.Join(ups, "\n")
+	if !strings.Contains(joined, "CREATE TABLE books") {
+		t.Errorf("expected CREATE TABLE books, got:\n%s", joined)
+	}
+	if !strings.Contains(joined, "DROP TABLE authors;") {
+		t.Errorf("expected DROP TABLE authors, got:\n%s", joined)
+	}
+}
+
+func TestDiffSnapshotsRenamedTable(t *testing.T) {
+	cols := []bdb.Column{{Name: "id", DBType: "integer"}, {Name: "name", DBType: "text"}}
+	from := &drivers.SchemaSnapshot{Tables: []bdb.Table{{Name: "authors", Columns: cols}}}
+	to := &drivers.SchemaSnapshot{Tables: []bdb.Table{{Name: "writers", Columns: cols}}}
+
+	ups, _ := stepStrings(diffSnapshots(from, to))
+	if len(ups) != 1 || ups[0] != "ALTER TABLE authors RENAME TO writers;" {
+		t.Errorf("expected a single table rename step, got: %v", ups)
+	}
+}
+
+func TestDiffColumnsRenamedColumn(t *testing.T) {
+	from := bdb.Table{Name: "authors", Columns: []bdb.Column{{Name: "full_name", DBType: "text"}}}
+	to := bdb.Table{Name: "authors", Columns: []bdb.Column{{Name: "name", DBType: "text"}}}
+
+	steps := diffColumns(from, to)
+	if len(steps) != 1 || steps[0].Up != "ALTER TABLE authors RENAME COLUMN full_name TO name;" {
+		t.Errorf("expected a single column rename step, got: %v", steps)
+	}
+}
+
+func TestDiffColumnsAddDropType(t *testing.T) {
+	from := bdb.Table{Name: "authors", Columns: []bdb.Column{
+		{Name: "id", DBType: "integer"},
+		{Name: "bio", DBType: "boolean"},
+	}}
+	to := bdb.Table{Name: "authors", Columns: []bdb.Column{
+		{Name: "id", DBType: "bigint"},
+		{Name: "email", DBType: "text"},
+	}}
+
+	ups, downs := stepStrings(diffColumns(from, to))
+	joinedUp := strings.Join(ups, "\n")
+	joinedDown := strings.Join(downs, "\n")
+
+	if !strings.Contains(joinedUp, "ADD COLUMN email text;") {
+		t.Errorf("expected ADD COLUMN email, got:\n%s", joinedUp)
+	}
+	if !strings.Contains(joinedUp, "ALTER COLUMN id TYPE bigint") {
+		t.Errorf("expected id type change, got:\n%s", joinedUp)
+	}
+	if !strings.Contains(joinedDown, "ADD COLUMN bio boolean;") {
+		t.Errorf("expected DROP of bio to reverse as ADD COLUMN, got:\n%s", joinedDown)
+	}
+}
+
+func TestDiffForeignKeysAddDrop(t *testing.T) {
+	from := bdb.Table{Name: "books", FKeys: []bdb.ForeignKey{
+		{Name: "books_author_id_fkey", Column: "author_id", ForeignTable: "authors", ForeignColumn: "id"},
+	}}
+	to := bdb.Table{Name: "books", FKeys: []bdb.ForeignKey{
+		{Name: "books_editor_id_fkey", Column: "editor_id", ForeignTable: "editors", ForeignColumn: "id"},
+	}}
+
+	ups, downs := stepStrings(diffForeignKeys(from, to))
+	joinedUp := strings.Join(ups, "\n")
+	joinedDown := strings.Join(downs, "\n")
+
+	if !strings.Contains(joinedUp, "ADD CONSTRAINT books_editor_id_fkey") {
+		t.Errorf("expected new FK to be added, got:\n%s", joinedUp)
+	}
+	if !strings.Contains(joinedDown, "ADD CONSTRAINT books_author_id_fkey") {
+		t.Errorf("expected dropped FK's down step to re-add it, got:\n%s", joinedDown)
+	}
+}
+
+func TestDiffSnapshotsCrossSchemaCollision(t *testing.T) {
+	from := &drivers.SchemaSnapshot{
+		Tables: []bdb.Table{
+			{Name: "users", Schema: "auth", Columns: []bdb.Column{{Name: "id", DBType: "integer"}}},
+			{Name: "users", Schema: "billing", Columns: []bdb.Column{{Name: "id", DBType: "integer"}, {Name: "plan", DBType: "text"}}},
+		},
+	}
+	to := &drivers.SchemaSnapshot{
+		Tables: []bdb.Table{
+			{Name: "users", Schema: "auth", Columns: []bdb.Column{{Name: "id", DBType: "integer"}, {Name: "email", DBType: "text"}}},
+			{Name: "users", Schema: "billing", Columns: []bdb.Column{{Name: "id", DBType: "integer"}, {Name: "plan", DBType: "text"}}},
+		},
+	}
+
+	ups, _ := stepStrings(diffSnapshots(from, to))
+	joined := strings.Join(ups, "\n")
+
+	if !strings.Contains(joined, "ALTER TABLE auth.users ADD COLUMN email text;") {
+		t.Errorf("expected auth.users to gain email, got:\n%s", joined)
+	}
+	if strings.Contains(joined, "billing.users") {
+		t.Errorf("billing.users is unchanged and should produce no steps, got:\n%s", joined)
+	}
+}
+
+func TestAddForeignKeySQLSchemaQualified(t *testing.T) {
+	table := bdb.Table{Name: "books", Schema: "catalog"}
+	fkey := bdb.ForeignKey{
+		Name: "books_author_id_fkey", Column: "author_id",
+		ForeignSchema: "people", ForeignTable: "authors", ForeignColumn: "id",
+	}
+
+	got := addForeignKeySQL(table, fkey)
+	want := "ALTER TABLE catalog.books ADD CONSTRAINT books_author_id_fkey FOREIGN KEY (author_id) REFERENCES people.authors (id);"
+	if got != want {
+		t.Errorf("addForeignKeySQL(...) = %q, want %q", got, want)
+	}
+}
+
+func TestDiffSnapshotsSkipsViews(t *testing.T) {
+	from := &drivers.SchemaSnapshot{}
+	to := &drivers.SchemaSnapshot{
+		Tables: []bdb.Table{
+			{Name: "authors", Kind: "table", Columns: []bdb.Column{{Name: "id", DBType: "integer"}}},
+			{Name: "author_summaries", Kind: "view", Columns: []bdb.Column{{Name: "id", DBType: "integer"}}},
+		},
+	}
+
+	ups, _ := stepStrings(diffSnapshots(from, to))
+	joined := strings.Join(ups, "\n")
+
+	if !strings.Contains(joined, "CREATE TABLE authors") {
+		t.Errorf("expected CREATE TABLE authors, got:\n%s", joined)
+	}
+	if strings.Contains(joined, "author_summaries") {
+		t.Errorf("a view should never get a CREATE TABLE step, got:\n%s", joined)
+	}
+}
+
+func TestDiffSnapshotsDeterministicOrder(t *testing.T) {
+	from := &drivers.SchemaSnapshot{}
+	to := &drivers.SchemaSnapshot{Tables: []bdb.Table{
+		{Name: "zebras", Columns: []bdb.Column{{Name: "id", DBType: "integer"}}},
+		{Name: "authors", Columns: []bdb.Column{{Name: "id", DBType: "integer"}}},
+	}}
+
+	for i := 0; i < 10; i++ {
+		ups, _ := stepStrings(diffSnapshots(from, to))
+		if !strings.Contains(ups[0], "authors") {
+			t.Fatalf("expected authors before zebras (sorted), got: %v", ups)
+		}
+	}
+}
+
+func TestGenerateMigrationWritesFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "migrate_test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	from := &drivers.SchemaSnapshot{}
+	to := &drivers.SchemaSnapshot{
+		Tables: []bdb.Table{{Name: "authors", Columns: []bdb.Column{{Name: "id", DBType: "integer"}}}},
+	}
+
+	upPath, downPath, err := GenerateMigration(from, to, "20060102150405", "add authors", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantUp := filepath.Join(dir, "20060102150405_add_authors.up.sql")
+	if upPath != wantUp {
+		t.Errorf("upPath = %q, want %q", upPath, wantUp)
+	}
+
+	upContents, err := ioutil.ReadFile(upPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(upContents), "CREATE TABLE authors") {
+		t.Errorf("up file missing CREATE TABLE, got:\n%s", upContents)
+	}
+
+	if _, err := os.Stat(downPath); err != nil {
+		t.Errorf("down file not written: %v", err)
+	}
+}
@@ -44,11 +44,12 @@ func textsFromForeignKey(packageName string, tables []bdb.Table, table bdb.Table
 
 	r.ForeignKey = fkey
 
-	r.LocalTable.NameGo = strmangle.TitleCase(strmangle.Singular(table.Name))
+	r.LocalTable.NameGo = tableNameGo(tables, table)
 	r.LocalTable.ColumnNameGo = strmangle.TitleCase(strmangle.Singular(fkey.Column))
 
+	foreignTable := bdb.GetTable(tables, fkey.ForeignSchema, fkey.ForeignTable)
 	r.ForeignTable.Name = fkey.ForeignTable
-	r.ForeignTable.NameGo = strmangle.TitleCase(strmangle.Singular(fkey.ForeignTable))
+	r.ForeignTable.NameGo = tableNameGo(tables, foreignTable)
 	r.ForeignTable.NamePluralGo = strmangle.TitleCase(strmangle.Plural(fkey.ForeignTable))
 	r.ForeignTable.ColumnName = fkey.ForeignColumn
 	r.ForeignTable.ColumnNameGo = strmangle.TitleCase(strmangle.Singular(fkey.ForeignColumn))
@@ -59,7 +60,7 @@ func textsFromForeignKey(packageName string, tables []bdb.Table, table bdb.Table
 	if fkey.Unique {
 		plurality = strmangle.Singular
 	}
-	r.Function.ForeignName = mkFunctionName(strmangle.Singular(fkey.ForeignTable), strmangle.TitleCase(plurality(fkey.Table)), fkey.Column, false)
+	r.Function.ForeignName = mkFunctionName(strmangle.Singular(fkey.ForeignTable), strmangle.TitleCase(plurality(fkey.Table)), fkey.Column, false, "")
 	r.Function.Varname = strmangle.CamelCase(strmangle.Singular(fkey.ForeignTable))
 	r.Function.Receiver = strings.ToLower(table.Name[:1])
 
@@ -71,7 +72,6 @@ func textsFromForeignKey(packageName string, tables []bdb.Table, table bdb.Table
 	}
 
 	if fkey.ForeignColumnNullable {
-		foreignTable := bdb.GetTable(tables, fkey.ForeignTable)
 		col := foreignTable.GetColumn(fkey.ForeignColumn)
 		r.Function.ForeignAssignment = fmt.Sprintf("%s.%s", strmangle.TitleCase(fkey.ForeignColumn), strings.TrimPrefix(col.Type, "null."))
 	} else {
@@ -90,6 +90,7 @@ func textsFromOneToOneRelationship(packageName string, tables []bdb.Table, table
 		Unique:   toMany.Unique,
 
 		ForeignTable:          toMany.ForeignTable,
+		ForeignSchema:         toMany.ForeignSchema,
 		ForeignColumn:         toMany.ForeignColumn,
 		ForeignColumnNullable: toMany.ForeignColumnNullable,
 		ForeignColumnUnique:   toMany.ForeignColumnUnique,
@@ -97,7 +98,7 @@ func textsFromOneToOneRelationship(packageName string, tables []bdb.Table, table
 
 	rel := textsFromForeignKey(packageName, tables, table, fkey)
 	rel.Function.Name = strmangle.TitleCase(strmangle.Singular(toMany.ForeignTable))
-	rel.Function.ForeignName = mkFunctionName(strmangle.Singular(toMany.Table), strmangle.TitleCase(strmangle.Singular(toMany.Table)), toMany.ForeignColumn, false)
+	rel.Function.ForeignName = mkFunctionName(strmangle.Singular(toMany.Table), strmangle.TitleCase(strmangle.Singular(toMany.Table)), toMany.ForeignColumn, false, "")
 	rel.Function.OneToOne = true
 	return rel
 }
@@ -134,18 +135,28 @@ type RelationshipToManyTexts struct {
 func textsFromRelationship(tables []bdb.Table, table bdb.Table, rel bdb.ToManyRelationship) RelationshipToManyTexts {
 	r := RelationshipToManyTexts{}
 	r.LocalTable.NameSingular = strmangle.Singular(table.Name)
-	r.LocalTable.NameGo = strmangle.TitleCase(r.LocalTable.NameSingular)
+	r.LocalTable.NameGo = tableNameGo(tables, table)
 	r.LocalTable.ColumnNameGo = strmangle.TitleCase(rel.Column)
 
+	foreignTable := bdb.GetTable(tables, rel.ForeignSchema, rel.ForeignTable)
 	r.ForeignTable.NameSingular = strmangle.Singular(rel.ForeignTable)
 	r.ForeignTable.NamePluralGo = strmangle.TitleCase(strmangle.Plural(rel.ForeignTable))
-	r.ForeignTable.NameGo = strmangle.TitleCase(r.ForeignTable.NameSingular)
+	r.ForeignTable.NameGo = tableNameGo(tables, foreignTable)
 	r.ForeignTable.ColumnNameGo = strmangle.TitleCase(rel.ForeignColumn)
 	r.ForeignTable.Slice = fmt.Sprintf("%sSlice", strmangle.TitleCase(r.ForeignTable.NameSingular))
 	r.ForeignTable.NameHumanReadable = strings.Replace(rel.ForeignTable, "_", " ", -1)
 
 	r.Function.Receiver = strings.ToLower(table.Name[:1])
-	r.Function.Name = mkFunctionName(r.LocalTable.NameSingular, r.ForeignTable.NamePluralGo, rel.ForeignColumn, rel.ToJoinTable)
+	nameCheck := r.LocalTable.NameSingular
+	nameColumn := rel.ForeignColumn
+	if rel.ToJoinTable {
+		// rel.JoinForeignColumn is the join table's column pointing at the
+		// foreign table, so the "is this the obvious name" check has to
+		// use it and compare against the foreign table, not the local one.
+		nameCheck = r.ForeignTable.NameSingular
+		nameColumn = rel.JoinForeignColumn
+	}
+	r.Function.Name = mkFunctionName(nameCheck, r.ForeignTable.NamePluralGo, nameColumn, rel.ToJoinTable, rel.JoinTable)
 	plurality := strmangle.Singular
 	foreignNamingColumn := rel.ForeignColumn
 	if rel.ToJoinTable {
@@ -162,7 +173,6 @@ func textsFromRelationship(tables []bdb.Table, table bdb.Table, rel bdb.ToManyRe
 	}
 
 	if rel.ForeignColumnNullable {
-		foreignTable := bdb.GetTable(tables, rel.ForeignTable)
 		col := foreignTable.GetColumn(rel.ForeignColumn)
 		r.Function.ForeignAssignment = fmt.Sprintf("%s.%s", strmangle.TitleCase(rel.ForeignColumn), strings.TrimPrefix(col.Type, "null."))
 	} else {
@@ -172,18 +182,149 @@ func textsFromRelationship(tables []bdb.Table, table bdb.Table, rel bdb.ToManyRe
 	return r
 }
 
+// tableNameGo returns the Go type name for a table. It is prefixed with
+// the TitleCased schema name (e.g. AuthUser, BillingUser) when another
+// schema in this run also has a table called table.Name, since in that
+// case the bare name would collide.
+func tableNameGo(tables []bdb.Table, table bdb.Table) string {
+	name := strmangle.TitleCase(strmangle.Singular(table.Name))
+	if !schemaCollides(tables, table) {
+		return name
+	}
+
+	return strmangle.TitleCase(table.Schema) + name
+}
+
+// schemaCollides reports whether some other table in tables shares
+// table's name but lives in a different schema.
+func schemaCollides(tables []bdb.Table, table bdb.Table) bool {
+	for _, other := range tables {
+		if other.Name == table.Name && other.Schema != table.Schema {
+			return true
+		}
+	}
+
+	return false
+}
+
 // mkFunctionName checks to see if the foreign key name is the same as the local table name (minus _id suffix)
 // Simple case: yes - we can name the function the same as the plural table name
-// Not simple case: We have to name the function based off the foreign key and the foreign table name
-func mkFunctionName(fkeyTableSingular, foreignTablePluralGo, fkeyColumn string, toJoinTable bool) string {
+// Not simple case: We have to name the function based off the foreign key and the foreign table name.
+// For join-table relationships, joinTableName disambiguates the through-relationship name when the FK
+// column name alone doesn't identify which join table is being traversed (e.g. a table joined to the
+// same foreign table through two different join tables).
+func mkFunctionName(fkeyTableSingular, foreignTablePluralGo, fkeyColumn string, toJoinTable bool, joinTableName string) string {
 	colName := strings.TrimSuffix(fkeyColumn, "_id")
-	if toJoinTable || fkeyTableSingular == colName {
+	if fkeyTableSingular == colName {
 		return foreignTablePluralGo
 	}
+	if toJoinTable {
+		return strmangle.TitleCase(strmangle.Singular(joinTableName)) + foreignTablePluralGo
+	}
 
 	return strmangle.TitleCase(colName) + foreignTablePluralGo
 }
 
+// JoinRowTexts contains text used by templates to generate a
+// first-class model for a many-to-many join table that carries columns
+// beyond its two foreign keys (e.g. created_at, role), along with
+// Add/Set/Remove helpers that accept those extra column values.
+type JoinRowTexts struct {
+	Table struct {
+		NameGo string
+		Name   string
+	}
+
+	Local struct {
+		NameGo   string
+		ColumnGo string
+	}
+
+	Foreign struct {
+		NameGo   string
+		ColumnGo string
+	}
+
+	ExtraColumns []string
+
+	Function struct {
+		Receiver string
+		Add      string
+		Set      string
+		Remove   string
+	}
+}
+
+// textsFromJoinTable builds the text used to generate a first-class
+// model for a join table that has columns beyond its two foreign keys.
+// Pure two-FK join tables keep using the plain ToJoinTable traversal in
+// textsFromRelationship.
+func textsFromJoinTable(tables []bdb.Table, joinTable bdb.Table, rel bdb.ToManyRelationship) JoinRowTexts {
+	r := JoinRowTexts{}
+
+	r.Table.Name = joinTable.Name
+	r.Table.NameGo = tableNameGo(tables, joinTable)
+
+	localTable := bdb.GetTable(tables, rel.Schema, rel.Table)
+	foreignTable := bdb.GetTable(tables, rel.ForeignSchema, rel.ForeignTable)
+
+	r.Local.NameGo = tableNameGo(tables, localTable)
+	r.Local.ColumnGo = strmangle.TitleCase(rel.JoinLocalColumn)
+
+	r.Foreign.NameGo = tableNameGo(tables, foreignTable)
+	r.Foreign.ColumnGo = strmangle.TitleCase(rel.JoinForeignColumn)
+
+	for _, col := range joinTable.Columns {
+		if col.Name == rel.JoinLocalColumn || col.Name == rel.JoinForeignColumn {
+			continue
+		}
+		r.ExtraColumns = append(r.ExtraColumns, col.Name)
+	}
+
+	r.Function.Receiver = strings.ToLower(localTable.Name[:1])
+	r.Function.Add = "Add" + r.Foreign.NameGo
+	r.Function.Set = "Set" + r.Foreign.NameGo
+	r.Function.Remove = "Remove" + r.Foreign.NameGo
+
+	return r
+}
+
+// FullTextSearchTexts contains text that will be used by templates to
+// generate a Search helper for a tsvector column.
+type FullTextSearchTexts struct {
+	Table struct {
+		NameGo string
+		Name   string
+	}
+
+	Column struct {
+		Name    string
+		Indexed bool
+	}
+
+	Function struct {
+		Name     string
+		Receiver string
+	}
+}
+
+// textsFromFullTextSearch creates a struct that does a lot of the text
+// transformation in advance for a given tsvector column.
+func textsFromFullTextSearch(table bdb.Table, col bdb.Column) FullTextSearchTexts {
+	r := FullTextSearchTexts{}
+
+	r.Table.Name = table.Name
+	r.Table.NameGo = strmangle.TitleCase(strmangle.Singular(table.Name))
+
+	r.Column.Name = col.Name
+	r.Column.Indexed = col.FullTextIndexed
+
+	r.Function.Receiver = strings.ToLower(table.Name[:1])
+	r.Function.Name = strmangle.TitleCase(strmangle.Singular(strings.TrimSuffix(col.Name, "_tsv"))) + "Search"
+
+	return r
+}
+
 // PreserveDot allows us to pass in templateData to relationship templates
 // called with the template function.
 type PreserveDot struct {
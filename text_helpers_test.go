@@ -0,0 +1,141 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/vattle/sqlboiler/bdb"
+)
+
+func TestMkFunctionName(t *testing.T) {
+	tests := []struct {
+		name              string
+		fkeyTableSingular string
+		fkeyColumn        string
+		toJoinTable       bool
+		joinTableName     string
+		want              string
+	}{
+		{"direct fk, simple", "author", "author_id", false, "", "Books"},
+		{"direct fk, ambiguous", "author", "editor_id", false, "", "EditorBooks"},
+		{"join table, ordinary", "book", "book_id", true, "author_books", "Books"},
+		{"join table, ambiguous", "book", "edited_book_id", true, "editor_books", "EditorBookBooks"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mkFunctionName(tt.fkeyTableSingular, "Books", tt.fkeyColumn, tt.toJoinTable, tt.joinTableName)
+			if got != tt.want {
+				t.Errorf("mkFunctionName(...) = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextsFromRelationshipOrdinaryJoinTableName(t *testing.T) {
+	tables := []bdb.Table{
+		{Name: "authors"},
+		{Name: "books"},
+		{Name: "author_books"},
+	}
+
+	rel := bdb.ToManyRelationship{
+		Column:            "id",
+		Table:             "authors",
+		ForeignTable:      "books",
+		ForeignColumn:     "book_id",
+		ToJoinTable:       true,
+		JoinTable:         "author_books",
+		JoinLocalColumn:   "author_id",
+		JoinForeignColumn: "book_id",
+	}
+
+	texts := textsFromRelationship(tables, bdb.GetTable(tables, "", "authors"), rel)
+	if want := "Books"; texts.Function.Name != want {
+		t.Errorf("Function.Name = %q, want %q (ordinary join accessors should not be renamed)", texts.Function.Name, want)
+	}
+}
+
+func TestTextsFromJoinTable(t *testing.T) {
+	tables := []bdb.Table{
+		{Name: "authors"},
+		{Name: "books"},
+		{
+			Name: "author_books",
+			Columns: []bdb.Column{
+				{Name: "author_id"},
+				{Name: "book_id"},
+				{Name: "royalty_split"},
+			},
+		},
+	}
+
+	rel := bdb.ToManyRelationship{
+		Table:             "authors",
+		ForeignTable:      "books",
+		ToJoinTable:       true,
+		JoinTable:         "author_books",
+		JoinLocalColumn:   "author_id",
+		JoinForeignColumn: "book_id",
+	}
+
+	texts := textsFromJoinTable(tables, bdb.GetTable(tables, "", "author_books"), rel)
+
+	if want := "AddBook"; texts.Function.Add != want {
+		t.Errorf("Function.Add = %q, want %q", texts.Function.Add, want)
+	}
+	if want := []string{"royalty_split"}; len(texts.ExtraColumns) != 1 || texts.ExtraColumns[0] != want[0] {
+		t.Errorf("ExtraColumns = %v, want %v", texts.ExtraColumns, want)
+	}
+}
+
+func TestTextsFromFullTextSearch(t *testing.T) {
+	table := bdb.Table{Name: "books"}
+	col := bdb.Column{Name: "body_tsv", FullText: true, FullTextIndexed: true}
+
+	texts := textsFromFullTextSearch(table, col)
+
+	if want := "BodySearch"; texts.Function.Name != want {
+		t.Errorf("Function.Name = %q, want %q", texts.Function.Name, want)
+	}
+	if !texts.Column.Indexed {
+		t.Error("Column.Indexed = false, want true")
+	}
+}
+
+func TestTableNameGoSchemaCollision(t *testing.T) {
+	tables := []bdb.Table{
+		{Name: "users", Schema: "auth"},
+		{Name: "users", Schema: "billing"},
+		{Name: "posts", Schema: "public"},
+	}
+
+	if want, got := "AuthUser", tableNameGo(tables, tables[0]); got != want {
+		t.Errorf("tableNameGo(auth.users) = %q, want %q", got, want)
+	}
+	if want, got := "BillingUser", tableNameGo(tables, tables[1]); got != want {
+		t.Errorf("tableNameGo(billing.users) = %q, want %q", got, want)
+	}
+	if want, got := "Post", tableNameGo(tables, tables[2]); got != want {
+		t.Errorf("tableNameGo(public.posts) = %q, want %q (no collision, no schema prefix)", got, want)
+	}
+}
+
+func TestGetTableSchemaCollision(t *testing.T) {
+	tables := []bdb.Table{
+		{Name: "users", Schema: "auth", Columns: []bdb.Column{{Name: "id"}}},
+		{Name: "users", Schema: "billing", Columns: []bdb.Column{{Name: "plan"}}},
+	}
+
+	got := bdb.GetTable(tables, "billing", "users")
+	if got.Schema != "billing" {
+		t.Fatalf("GetTable(..., %q, %q).Schema = %q, want %q", "billing", "users", got.Schema, "billing")
+	}
+	if col := got.GetColumn("plan"); col.Name != "plan" {
+		t.Errorf("GetTable with schema returned the wrong table's columns: %+v", got)
+	}
+
+	got = bdb.GetTable(tables, "auth", "users")
+	if got.Schema != "auth" {
+		t.Fatalf("GetTable(..., %q, %q).Schema = %q, want %q", "auth", "users", got.Schema, "auth")
+	}
+}